@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// thresholds are the operator-configured limits checked against every
+// health snapshot. A zero value for a given field means "unchecked" —
+// there's no sensible zero threshold for any of these metrics.
+type thresholds struct {
+	maxQueue     int
+	maxAvgRunMs  float64
+	maxAvgWaitMs float64
+	minPool      int
+}
+
+func (t thresholds) empty() bool {
+	return t.maxQueue <= 0 && t.maxAvgRunMs <= 0 && t.maxAvgWaitMs <= 0 && t.minPool <= 0
+}
+
+// breach describes a single threshold violation, in the shape posted to
+// -alert-webhook (Alertmanager-webhook and Slack/Discord incoming webhooks
+// can both consume flat JSON like this).
+type breach struct {
+	Metric     string    `json:"metric"`
+	Value      float64   `json:"value"`
+	Threshold  float64   `json:"threshold"`
+	Timestamp  time.Time `json:"timestamp"`
+	ServiceURL string    `json:"serviceUrl"`
+}
+
+func (t thresholds) evaluate(health healthResponse, serviceURL string, now time.Time) []breach {
+	var breaches []breach
+	add := func(metric string, value, threshold float64) {
+		breaches = append(breaches, breach{Metric: metric, Value: value, Threshold: threshold, Timestamp: now, ServiceURL: serviceURL})
+	}
+
+	if t.maxQueue > 0 && health.QueueLength > t.maxQueue {
+		add("queueLength", float64(health.QueueLength), float64(t.maxQueue))
+	}
+	if t.maxAvgRunMs > 0 && health.AvgRunMs > t.maxAvgRunMs {
+		add("avgRunMs", health.AvgRunMs, t.maxAvgRunMs)
+	}
+	if t.maxAvgWaitMs > 0 && health.AvgQueueWaitMs > t.maxAvgWaitMs {
+		add("avgQueueWaitMs", health.AvgQueueWaitMs, t.maxAvgWaitMs)
+	}
+	if t.minPool > 0 && health.PoolSize < t.minPool {
+		add("poolSize", float64(health.PoolSize), float64(t.minPool))
+	}
+	return breaches
+}
+
+// alerter posts breaches to a webhook, suppressing repeat fires for the
+// same metric within the cooldown window so a flapping metric doesn't spam
+// the receiver.
+type alerter struct {
+	webhookURL string
+	cooldown   time.Duration
+	lastFired  map[string]time.Time
+}
+
+func newAlerter(webhookURL string, cooldown time.Duration) *alerter {
+	return &alerter{webhookURL: webhookURL, cooldown: cooldown, lastFired: make(map[string]time.Time)}
+}
+
+func (a *alerter) fire(b breach) {
+	if a == nil || a.webhookURL == "" {
+		return
+	}
+	if last, ok := a.lastFired[b.Metric]; ok && b.Timestamp.Sub(last) < a.cooldown {
+		return
+	}
+	a.lastFired[b.Metric] = b.Timestamp
+	go a.post(b)
+}
+
+func (a *alerter) post(b breach) {
+	payload, err := json.Marshal(b)
+	if err != nil {
+		return
+	}
+	resp, err := http.Post(a.webhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  alert webhook failed: %v\n", err)
+		return
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThresholdsEvaluate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	serviceURL := "http://127.0.0.1:4001"
+
+	tests := []struct {
+		name    string
+		t       thresholds
+		health  healthResponse
+		metrics []string // metric names expected to breach, in order
+	}{
+		{
+			name:   "no thresholds configured",
+			t:      thresholds{},
+			health: healthResponse{QueueLength: 1000, AvgRunMs: 1000, AvgQueueWaitMs: 1000, PoolSize: 0},
+		},
+		{
+			name:   "under every limit",
+			t:      thresholds{maxQueue: 10, maxAvgRunMs: 500, maxAvgWaitMs: 200, minPool: 2},
+			health: healthResponse{QueueLength: 5, AvgRunMs: 100, AvgQueueWaitMs: 50, PoolSize: 4},
+		},
+		{
+			name:    "queue length breach only",
+			t:       thresholds{maxQueue: 10},
+			health:  healthResponse{QueueLength: 11},
+			metrics: []string{"queueLength"},
+		},
+		{
+			name:    "at the limit does not breach",
+			t:       thresholds{maxQueue: 10, minPool: 2},
+			health:  healthResponse{QueueLength: 10, PoolSize: 2},
+			metrics: nil,
+		},
+		{
+			name:    "min pool breach only",
+			t:       thresholds{minPool: 4},
+			health:  healthResponse{PoolSize: 3},
+			metrics: []string{"poolSize"},
+		},
+		{
+			name:    "all four breach at once, in struct order",
+			t:       thresholds{maxQueue: 1, maxAvgRunMs: 1, maxAvgWaitMs: 1, minPool: 10},
+			health:  healthResponse{QueueLength: 2, AvgRunMs: 2, AvgQueueWaitMs: 2, PoolSize: 1},
+			metrics: []string{"queueLength", "avgRunMs", "avgQueueWaitMs", "poolSize"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			breaches := tc.t.evaluate(tc.health, serviceURL, now)
+			if len(breaches) != len(tc.metrics) {
+				t.Fatalf("evaluate() returned %d breaches, want %d: %+v", len(breaches), len(tc.metrics), breaches)
+			}
+			for i, want := range tc.metrics {
+				got := breaches[i]
+				if got.Metric != want {
+					t.Errorf("breach[%d].Metric = %q, want %q", i, got.Metric, want)
+				}
+				if got.ServiceURL != serviceURL {
+					t.Errorf("breach[%d].ServiceURL = %q, want %q", i, got.ServiceURL, serviceURL)
+				}
+				if !got.Timestamp.Equal(now) {
+					t.Errorf("breach[%d].Timestamp = %v, want %v", i, got.Timestamp, now)
+				}
+			}
+		})
+	}
+}
+
+func TestThresholdsEmpty(t *testing.T) {
+	tests := []struct {
+		name string
+		t    thresholds
+		want bool
+	}{
+		{"zero value", thresholds{}, true},
+		{"negative fields still count as unchecked", thresholds{maxQueue: -1, maxAvgRunMs: -1}, true},
+		{"one field set", thresholds{maxQueue: 1}, false},
+		{"all fields set", thresholds{maxQueue: 1, maxAvgRunMs: 1, maxAvgWaitMs: 1, minPool: 1}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.t.empty(); got != tc.want {
+				t.Errorf("empty() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -1,13 +1,14 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"os/exec"
+	"os/signal"
 	"strings"
 	"time"
 )
@@ -42,22 +43,46 @@ type metricsResponse struct {
 	} `json:"stats"`
 }
 
-func checkHealth(serviceURL string) error {
+func fetchHealth(serviceURL string) (healthResponse, error) {
+	var health healthResponse
+
 	resp, err := http.Get(serviceURL + "/healthz")
 	if err != nil {
-		return fmt.Errorf("failed to connect: %v", err)
+		return health, fmt.Errorf("failed to connect: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("service returned status %d", resp.StatusCode)
+		return health, fmt.Errorf("service returned status %d", resp.StatusCode)
 	}
 
-	var health healthResponse
 	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
-		return fmt.Errorf("failed to parse response: %v", err)
+		return health, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return health, nil
+}
+
+func fetchMetrics(serviceURL string) (metricsResponse, error) {
+	var metrics metricsResponse
+
+	resp, err := http.Get(serviceURL + "/metrics")
+	if err != nil {
+		return metrics, fmt.Errorf("failed to connect: %v", err)
 	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return metrics, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if err := json.Unmarshal(body, &metrics); err != nil {
+		return metrics, fmt.Errorf("failed to parse response: %v", err)
+	}
+	return metrics, nil
+}
 
+func printHealth(health healthResponse) {
 	fmt.Println("✅ Runner Service Health Check")
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Printf("Status:        %s\n", health.Status)
@@ -70,24 +95,28 @@ func checkHealth(serviceURL string) error {
 		fmt.Printf("Avg Run Time:  %.2f ms\n", health.AvgRunMs)
 		fmt.Printf("Avg Wait Time: %.2f ms\n", health.AvgQueueWaitMs)
 	}
-	return nil
 }
 
-func showMetrics(serviceURL string) error {
-	resp, err := http.Get(serviceURL + "/metrics")
+func checkHealth(serviceURL string, t thresholds) error {
+	health, err := fetchHealth(serviceURL)
 	if err != nil {
-		return fmt.Errorf("failed to connect: %v", err)
+		return err
 	}
-	defer resp.Body.Close()
+	printHealth(health)
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %v", err)
+	if breaches := t.evaluate(health, serviceURL, time.Now()); len(breaches) > 0 {
+		for _, b := range breaches {
+			fmt.Printf("🚨 THRESHOLD BREACHED: %s=%.2f (limit %.2f)\n", b.Metric, b.Value, b.Threshold)
+		}
+		return fmt.Errorf("%d threshold(s) breached", len(breaches))
 	}
+	return nil
+}
 
-	var metrics metricsResponse
-	if err := json.Unmarshal(body, &metrics); err != nil {
-		return fmt.Errorf("failed to parse response: %v", err)
+func showMetrics(serviceURL string) error {
+	metrics, err := fetchMetrics(serviceURL)
+	if err != nil {
+		return err
 	}
 
 	fmt.Println("📊 Runner Service Metrics")
@@ -110,22 +139,20 @@ func showMetrics(serviceURL string) error {
 	return nil
 }
 
-func cleanupContainers(prefix string) error {
-	cmd := exec.Command("docker", "ps", "-a", "--filter", fmt.Sprintf("name=%s", prefix), "--format", "{{.Names}}")
-	output, err := cmd.Output()
+func cleanupContainers(runtime ContainerRuntime, prefix string) error {
+	containers, err := runtime.List(prefix)
 	if err != nil {
 		return fmt.Errorf("failed to list containers: %v", err)
 	}
 
-	containers := strings.Fields(string(output))
 	if len(containers) == 0 {
 		fmt.Printf("No containers found with prefix '%s'\n", prefix)
 		return nil
 	}
 
-	fmt.Printf("Found %d container(s) to remove:\n", len(containers))
-	for _, name := range containers {
-		fmt.Printf("  - %s\n", name)
+	fmt.Printf("Found %d container(s) to remove (%s):\n", len(containers), runtime.Name())
+	for _, c := range containers {
+		fmt.Printf("  - %s\n", c.Name)
 	}
 
 	fmt.Print("\nRemove these containers? (y/N): ")
@@ -136,50 +163,72 @@ func cleanupContainers(prefix string) error {
 		return nil
 	}
 
-	for _, name := range containers {
-		cmd := exec.Command("docker", "rm", "-f", name)
-		if err := cmd.Run(); err != nil {
-			fmt.Printf("⚠️  Failed to remove %s: %v\n", name, err)
+	for _, c := range containers {
+		if err := runtime.Remove(c.ID, true); err != nil {
+			fmt.Printf("⚠️  Failed to remove %s: %v\n", c.Name, err)
 		} else {
-			fmt.Printf("✅ Removed %s\n", name)
+			fmt.Printf("✅ Removed %s\n", c.Name)
 		}
 	}
 	return nil
 }
 
-func watchHealth(serviceURL string, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+func main() {
+	var (
+		health         = flag.Bool("health", false, "Check service health")
+		metrics        = flag.Bool("metrics", false, "Show detailed metrics")
+		cleanup        = flag.Bool("cleanup", false, "Clean up runner containers")
+		watch          = flag.Bool("watch", false, "Watch health status continuously")
+		tui            = flag.Bool("tui", false, "Launch an interactive full-screen dashboard")
+		stats          = flag.Bool("stats", false, "Stream live CPU/memory/network stats for matching containers")
+		verbose        = flag.Bool("verbose", false, "Show a per-container breakdown with -stats")
+		statsInterval  = flag.Duration("stats-interval", 2*time.Second, "Summary print interval for -stats")
+		prefix         = flag.String("prefix", "judge-python-worker", "Container name prefix for cleanup")
+		runtimeKind    = flag.String("runtime", "auto", "Container backend: docker, podman, containerd, or auto")
+		serviceURL     = flag.String("url", defaultServiceURL, "Runner service URL")
+		interval       = flag.Duration("interval", 5*time.Second, "Watch interval (for -watch)")
+		prometheus     = flag.Bool("prometheus", false, "Print a one-shot Prometheus exposition scrape")
+		serveExporter  = flag.String("serve-exporter", "", "Run a Prometheus exporter on this address (e.g. :9100) that re-publishes runner metrics")
+		scrapeInterval = flag.Duration("scrape-interval", 15*time.Second, "Scrape interval for -serve-exporter")
+		format         = flag.String("format", "pretty", "Watch output format: pretty, json, ndjson, or csv")
+		out            = flag.String("out", "", "Write -watch output to this file instead of stdout (enables log rotation)")
+		logMaxSize     = flag.Int64("log-max-size", 10*1024*1024, "Rotate -out once it exceeds this many bytes (0 disables rotation)")
+		logKeep        = flag.Int("log-keep", 5, "Number of rotated -out files to keep (0 truncates -out in place on rotation instead of keeping history)")
+		ewmaAlpha      = flag.Float64("ewma-alpha", 0.3, "Smoothing factor for the avgRunMs EWMA in -watch output")
+		maxQueue       = flag.Int("max-queue", 0, "Alert/fail if queue length exceeds this (0 disables)")
+		maxAvgRunMs    = flag.Float64("max-avg-run-ms", 0, "Alert/fail if avg run time exceeds this many ms (0 disables)")
+		maxAvgWaitMs   = flag.Float64("max-avg-wait-ms", 0, "Alert/fail if avg queue wait exceeds this many ms (0 disables)")
+		minPool        = flag.Int("min-pool", 0, "Alert/fail if pool size drops below this (0 disables)")
+		alertWebhook   = flag.String("alert-webhook", "", "POST a JSON payload here when a -watch threshold is breached")
+		alertCooldown  = flag.Duration("alert-cooldown", 5*time.Minute, "Minimum time between repeat alerts for the same metric")
+	)
+	flag.Parse()
 
-	fmt.Printf("Watching runner service health (every %v)...\n", interval)
-	fmt.Println("Press Ctrl+C to stop\n")
+	limits := thresholds{
+		maxQueue:     *maxQueue,
+		maxAvgRunMs:  *maxAvgRunMs,
+		maxAvgWaitMs: *maxAvgWaitMs,
+		minPool:      *minPool,
+	}
 
-	for {
-		select {
-		case <-ticker.C:
-			fmt.Printf("[%s] ", time.Now().Format("15:04:05"))
-			if err := checkHealth(serviceURL); err != nil {
-				fmt.Printf("❌ Error: %v\n", err)
-			}
-			fmt.Println()
+	if *prometheus {
+		if err := showPrometheus(*serviceURL); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
+		return
 	}
-}
 
-func main() {
-	var (
-		health      = flag.Bool("health", false, "Check service health")
-		metrics     = flag.Bool("metrics", false, "Show detailed metrics")
-		cleanup     = flag.Bool("cleanup", false, "Clean up runner containers")
-		watch       = flag.Bool("watch", false, "Watch health status continuously")
-		prefix      = flag.String("prefix", "judge-python-worker", "Container name prefix for cleanup")
-		serviceURL  = flag.String("url", defaultServiceURL, "Runner service URL")
-		interval    = flag.Duration("interval", 5*time.Second, "Watch interval (for -watch)")
-	)
-	flag.Parse()
+	if *serveExporter != "" {
+		if err := runExporter(*serviceURL, *serveExporter, *scrapeInterval); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	if *health {
-		if err := checkHealth(*serviceURL); err != nil {
+		if err := checkHealth(*serviceURL, limits); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -195,7 +244,40 @@ func main() {
 	}
 
 	if *cleanup {
-		if err := cleanupContainers(*prefix); err != nil {
+		runtime, err := selectRuntime(*runtimeKind)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := cleanupContainers(runtime, *prefix); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *tui {
+		runtime, err := selectRuntime(*runtimeKind)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := runTUI(*serviceURL, runtime, *prefix, *interval); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *stats {
+		runtime, err := selectRuntime(*runtimeKind)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		if err := runStats(ctx, runtime, *prefix, *statsInterval, *verbose); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -203,12 +285,28 @@ func main() {
 	}
 
 	if *watch {
-		watchHealth(*serviceURL, *interval)
+		var w io.Writer = os.Stdout
+		if *out != "" {
+			rw, err := newRotatingWriter(*out, *logMaxSize, *logKeep)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			defer rw.Close()
+			w = rw
+		}
+		watchHealth(*serviceURL, *interval, watchOptions{
+			format:     watchFormat(*format),
+			out:        w,
+			alpha:      *ewmaAlpha,
+			thresholds: limits,
+			alerter:    newAlerter(*alertWebhook, *alertCooldown),
+		})
 		return
 	}
 
 	// Default: show health
-	if err := checkHealth(*serviceURL); err != nil {
+	if err := checkHealth(*serviceURL, limits); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// promLabels renders the standard {image="...",service="..."} label set
+// shared by every series this exporter emits.
+func promLabels(image, serviceURL string) string {
+	return fmt.Sprintf("image=%q,service=%q", image, serviceURL)
+}
+
+// renderPrometheus converts a healthResponse/metricsResponse pair into
+// Prometheus text exposition format. The runner service only reports
+// running averages rather than per-run samples, so runner_run_duration_ms
+// and runner_queue_wait_ms are exposed as gauges (the average since the
+// service started) instead of true histograms.
+func renderPrometheus(health healthResponse, metrics metricsResponse, serviceURL string) string {
+	labels := promLabels(health.Image, serviceURL)
+	var b strings.Builder
+
+	writeGauge := func(name, help string, value float64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n", name, help)
+		fmt.Fprintf(&b, "# TYPE %s gauge\n", name)
+		fmt.Fprintf(&b, "%s{%s} %g\n", name, labels, value)
+	}
+	writeCounter := func(name, help string, value float64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n", name, help)
+		fmt.Fprintf(&b, "# TYPE %s counter\n", name)
+		fmt.Fprintf(&b, "%s{%s} %g\n", name, labels, value)
+	}
+
+	writeGauge("runner_pool_size", "Configured worker pool size.", float64(metrics.Stats.PoolSize))
+	writeGauge("runner_active_workers", "Workers currently executing a job.", float64(metrics.Stats.ActiveWorkers))
+	writeGauge("runner_queue_length", "Jobs waiting for a free worker.", float64(metrics.Stats.QueueLength))
+	writeCounter("runner_total_runs_total", "Total runs completed since service start.", float64(metrics.Stats.TotalRuns))
+	writeGauge("runner_run_duration_ms", "Average run duration in milliseconds.", metrics.Stats.AvgRunMs)
+	writeGauge("runner_queue_wait_ms", "Average time a job waits in queue before running, in milliseconds.", metrics.Stats.AvgQueueWaitMs)
+	writeGauge("runner_uptime_seconds", "Seconds since the runner service started.", metrics.UptimeSeconds)
+	writeGauge("runner_memory_rss_bytes", "Resident set size of the runner service process.", float64(metrics.Memory.RSS))
+	writeGauge("runner_memory_heap_used_bytes", "Heap memory in use by the runner service process.", float64(metrics.Memory.HeapUsed))
+
+	return b.String()
+}
+
+// fetchPrometheus scrapes /healthz and /metrics from the runner service and
+// renders them as Prometheus exposition text.
+func fetchPrometheus(serviceURL string) (string, error) {
+	health, err := fetchHealth(serviceURL)
+	if err != nil {
+		return "", err
+	}
+	metrics, err := fetchMetrics(serviceURL)
+	if err != nil {
+		return "", err
+	}
+	return renderPrometheus(health, metrics, serviceURL), nil
+}
+
+// showPrometheus prints the Prometheus exposition text for a single scrape
+// to stdout, for use with `-prometheus` (e.g. piping into `promtool check
+// metrics` or a one-shot curl-free scrape).
+func showPrometheus(serviceURL string) error {
+	text, err := fetchPrometheus(serviceURL)
+	if err != nil {
+		return err
+	}
+	fmt.Print(text)
+	return nil
+}
+
+// promCache holds the last scrape's rendered text (or error) so concurrent
+// /metrics requests never block on, or amplify load against, the runner.
+type promCache struct {
+	mu   sync.RWMutex
+	text string
+	err  error
+}
+
+func (c *promCache) set(text string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.text = text
+	}
+	c.err = err
+}
+
+func (c *promCache) get() (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.text, c.err
+}
+
+// runExporter starts an HTTP server on listenAddr that re-publishes the
+// runner service's stats in Prometheus format on /metrics. A background
+// loop scrapes the runner every scrapeInterval into a cache; the handler
+// always serves the cached text, so a slow or unavailable runner can't
+// turn a Prometheus scrape into a synchronous round-trip (or a 502) against
+// the runner. This lets the CLI sit alongside an existing Prometheus/Grafana
+// stack without the runner service itself needing to speak the exposition
+// format.
+func runExporter(serviceURL, listenAddr string, scrapeInterval time.Duration) error {
+	fmt.Printf("Serving Prometheus metrics on %s/metrics (scraping %s every %v)\n", listenAddr, serviceURL, scrapeInterval)
+
+	cache := &promCache{}
+	scrape := func() {
+		text, err := fetchPrometheus(serviceURL)
+		cache.set(text, err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  scrape of %s failed: %v\n", serviceURL, err)
+		}
+	}
+	scrape()
+
+	ticker := time.NewTicker(scrapeInterval)
+	defer ticker.Stop()
+	go func() {
+		for range ticker.C {
+			scrape()
+		}
+	}()
+
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		text, err := cache.get()
+		if err != nil && text == "" {
+			http.Error(w, fmt.Sprintf("last scrape failed: %v", err), http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		io.WriteString(w, text)
+	})
+
+	return http.ListenAndServe(listenAddr, nil)
+}
@@ -0,0 +1,415 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containerd/cgroups/v3/cgroup1/stats"
+	"github.com/containerd/containerd"
+	v1types "github.com/containerd/containerd/api/types"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/typeurl/v2"
+)
+
+const (
+	dockerSocket                = "/var/run/docker.sock"
+	podmanSocket                = "/run/podman/podman.sock"
+	containerdSocket            = "/run/containerd/containerd.sock"
+	containerdNamespace         = "default"
+	containerdStatsPollInterval = time.Second
+)
+
+// Container is a runtime-agnostic view of a single worker container,
+// enough to list and clean it up regardless of which backend produced it.
+type Container struct {
+	ID     string
+	Name   string
+	Image  string
+	Status string
+}
+
+// ContainerRuntime abstracts over the container backend so that
+// cleanupContainers (and future introspection commands) work the same way
+// on Docker, Podman, and containerd hosts.
+type ContainerRuntime interface {
+	Name() string
+	List(prefix string) ([]Container, error)
+	Remove(id string, force bool) error
+	Stats(ctx context.Context, id string) (<-chan ContainerStats, error)
+}
+
+// selectRuntime resolves the -runtime flag to a concrete ContainerRuntime.
+// "auto" probes the well-known sockets in Docker, Podman, containerd order.
+func selectRuntime(kind string) (ContainerRuntime, error) {
+	switch kind {
+	case "docker":
+		return dockerRuntime{socketPath: dockerSocket}, nil
+	case "podman":
+		return newPodmanRuntime(podmanSocket), nil
+	case "containerd":
+		return newContainerdRuntime(containerdSocket, containerdNamespace), nil
+	case "auto", "":
+		return autoDetectRuntime()
+	default:
+		return nil, fmt.Errorf("unknown runtime %q (want docker, podman, containerd, or auto)", kind)
+	}
+}
+
+func autoDetectRuntime() (ContainerRuntime, error) {
+	if socketExists(dockerSocket) {
+		return dockerRuntime{socketPath: dockerSocket}, nil
+	}
+	if socketExists(podmanSocket) {
+		return newPodmanRuntime(podmanSocket), nil
+	}
+	if socketExists(containerdSocket) {
+		return newContainerdRuntime(containerdSocket, containerdNamespace), nil
+	}
+	return nil, fmt.Errorf("no container runtime detected (checked %s, %s, %s)", dockerSocket, podmanSocket, containerdSocket)
+}
+
+func socketExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode()&os.ModeSocket != 0
+}
+
+// --- Docker: shells out to the docker CLI for listing/removal (matching the
+// tool's original behavior), but talks to the daemon socket directly for
+// stats streaming since there's no CLI equivalent of `docker stats --format json`
+// that's stable to parse.
+
+type dockerRuntime struct {
+	socketPath string
+}
+
+func (dockerRuntime) Name() string { return "docker" }
+
+func (d dockerRuntime) streamingClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", d.socketPath)
+			},
+		},
+	}
+}
+
+func (d dockerRuntime) Stats(ctx context.Context, id string) (<-chan ContainerStats, error) {
+	return streamContainerStats(ctx, d.streamingClient(), "http://d/v1.41", id)
+}
+
+func (dockerRuntime) List(prefix string) ([]Container, error) {
+	cmd := exec.Command("docker", "ps", "-a",
+		"--filter", fmt.Sprintf("name=%s", prefix),
+		"--format", "{{.ID}}\t{{.Names}}\t{{.Image}}\t{{.Status}}")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker ps failed: %v", err)
+	}
+	return parsePSTable(output), nil
+}
+
+func (dockerRuntime) Remove(id string, force bool) error {
+	args := []string{"rm"}
+	if force {
+		args = append(args, "-f")
+	}
+	return exec.Command("docker", append(args, id)...).Run()
+}
+
+func parsePSTable(output []byte) []Container {
+	var containers []Container
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		var c Container
+		if len(fields) > 0 {
+			c.ID = fields[0]
+		}
+		if len(fields) > 1 {
+			c.Name = fields[1]
+		}
+		if len(fields) > 2 {
+			c.Image = fields[2]
+		}
+		if len(fields) > 3 {
+			c.Status = fields[3]
+		}
+		containers = append(containers, c)
+	}
+	return containers
+}
+
+// --- Podman: talks to the libpod REST socket's Docker-compat API. ---
+
+type podmanRuntime struct {
+	socketPath string
+}
+
+func newPodmanRuntime(socketPath string) *podmanRuntime {
+	return &podmanRuntime{socketPath: socketPath}
+}
+
+func (p *podmanRuntime) Name() string { return "podman" }
+
+func (p *podmanRuntime) client() *http.Client {
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", p.socketPath)
+			},
+		},
+	}
+}
+
+type podmanContainerSummary struct {
+	ID     string   `json:"Id"`
+	Names  []string `json:"Names"`
+	Image  string   `json:"Image"`
+	Status string   `json:"Status"`
+}
+
+func (p *podmanRuntime) List(prefix string) ([]Container, error) {
+	filters, err := json.Marshal(map[string][]string{"name": {prefix}})
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{"all": {"true"}, "filters": {string(filters)}}
+	resp, err := p.client().Get("http://d/v1.41/containers/json?" + q.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("podman socket request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var summaries []podmanContainerSummary
+	if err := json.NewDecoder(resp.Body).Decode(&summaries); err != nil {
+		return nil, fmt.Errorf("failed to parse podman response: %v", err)
+	}
+
+	containers := make([]Container, 0, len(summaries))
+	for _, s := range summaries {
+		name := s.ID
+		if len(s.Names) > 0 {
+			name = strings.TrimPrefix(s.Names[0], "/")
+		}
+		containers = append(containers, Container{ID: s.ID, Name: name, Image: s.Image, Status: s.Status})
+	}
+	return containers, nil
+}
+
+func (p *podmanRuntime) streamingClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", p.socketPath)
+			},
+		},
+	}
+}
+
+func (p *podmanRuntime) Stats(ctx context.Context, id string) (<-chan ContainerStats, error) {
+	return streamContainerStats(ctx, p.streamingClient(), "http://d/v1.41", id)
+}
+
+func (p *podmanRuntime) Remove(id string, force bool) error {
+	q := url.Values{"force": {fmt.Sprintf("%v", force)}, "v": {"1"}}
+	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("http://d/v1.41/containers/%s?%s", id, q.Encode()), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return fmt.Errorf("podman socket request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("podman returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// --- containerd: uses the official Go client against the containerd socket. ---
+
+type containerdRuntime struct {
+	socketPath string
+	namespace  string
+}
+
+func newContainerdRuntime(socketPath, namespace string) *containerdRuntime {
+	return &containerdRuntime{socketPath: socketPath, namespace: namespace}
+}
+
+func (c *containerdRuntime) Name() string { return "containerd" }
+
+func (c *containerdRuntime) List(prefix string) ([]Container, error) {
+	client, err := containerd.New(c.socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("connect to containerd: %v", err)
+	}
+	defer client.Close()
+
+	ctx := namespaces.WithNamespace(context.Background(), c.namespace)
+	all, err := client.Containers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list containers: %v", err)
+	}
+
+	var containers []Container
+	for _, ctr := range all {
+		if !strings.Contains(ctr.ID(), prefix) {
+			continue
+		}
+		info, err := ctr.Info(ctx)
+		if err != nil {
+			continue
+		}
+		status := "created"
+		if task, err := ctr.Task(ctx, nil); err == nil {
+			if s, err := task.Status(ctx); err == nil {
+				status = string(s.Status)
+			}
+		}
+		containers = append(containers, Container{ID: ctr.ID(), Name: ctr.ID(), Image: info.Image, Status: status})
+	}
+	return containers, nil
+}
+
+// parseContainerdMetrics extracts cumulative CPU usage (nanoseconds) and
+// current memory usage/limit from a task's raw cgroup v1 metrics. Hosts
+// running cgroup v2 would need the v2 stats type instead; this covers the
+// common cgroup v1 containerd deployment this tool targets.
+func parseContainerdMetrics(metrics *v1types.Metric) (cpuUsageNs uint64, memUsage, memLimit int64) {
+	data, err := typeurl.UnmarshalAny(metrics.Data)
+	if err != nil {
+		return 0, 0, 0
+	}
+	m, ok := data.(*stats.Metrics)
+	if !ok || m.CPU == nil {
+		return 0, 0, 0
+	}
+	if m.CPU.Usage != nil {
+		cpuUsageNs = m.CPU.Usage.Total
+	}
+	if m.Memory != nil && m.Memory.Usage != nil {
+		memUsage = int64(m.Memory.Usage.Usage)
+		memLimit = int64(m.Memory.Usage.Limit)
+	}
+	return cpuUsageNs, memUsage, memLimit
+}
+
+// Stats polls the task's cgroup metrics since containerd, unlike the
+// Docker/Podman compat API, has no streaming stats endpoint of its own.
+func (c *containerdRuntime) Stats(ctx context.Context, id string) (<-chan ContainerStats, error) {
+	client, err := containerd.New(c.socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("connect to containerd: %v", err)
+	}
+
+	nsCtx := namespaces.WithNamespace(ctx, c.namespace)
+	ctr, err := client.LoadContainer(nsCtx, id)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("load container %s: %v", id, err)
+	}
+	task, err := ctr.Task(nsCtx, nil)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("load task %s: %v", id, err)
+	}
+
+	ch := make(chan ContainerStats)
+	go func() {
+		defer close(ch)
+		defer client.Close()
+
+		var prevUsage uint64
+		var prevTime time.Time
+		ticker := time.NewTicker(containerdStatsPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			metrics, err := task.Metrics(nsCtx)
+			if err != nil {
+				continue
+			}
+			usage, memUsage, memLimit := parseContainerdMetrics(metrics)
+			now := time.Now()
+
+			var cpuPercent float64
+			if !prevTime.IsZero() && usage > prevUsage {
+				elapsedNs := float64(now.Sub(prevTime).Nanoseconds())
+				if elapsedNs > 0 {
+					cpuPercent = float64(usage-prevUsage) / elapsedNs * 100
+				}
+			}
+			prevUsage, prevTime = usage, now
+
+			select {
+			case ch <- ContainerStats{ContainerID: id, CPUPercent: cpuPercent, MemUsage: memUsage, MemLimit: memLimit}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func (c *containerdRuntime) Remove(id string, force bool) error {
+	client, err := containerd.New(c.socketPath)
+	if err != nil {
+		return fmt.Errorf("connect to containerd: %v", err)
+	}
+	defer client.Close()
+
+	ctx := namespaces.WithNamespace(context.Background(), c.namespace)
+	ctr, err := client.LoadContainer(ctx, id)
+	if err != nil {
+		return fmt.Errorf("load container %s: %v", id, err)
+	}
+
+	if task, err := ctr.Task(ctx, nil); err == nil {
+		// Kill only requests termination; containerd rejects Delete until the
+		// task has actually reached Stopped, so wait on the task's exit
+		// channel before deleting it.
+		exitCh, err := task.Wait(ctx)
+		if err != nil {
+			return fmt.Errorf("wait task %s: %v", id, err)
+		}
+		if force {
+			if err := task.Kill(ctx, syscall.SIGKILL); err != nil {
+				return fmt.Errorf("kill task %s: %v", id, err)
+			}
+		}
+		select {
+		case <-exitCh:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if _, err := task.Delete(ctx); err != nil {
+			return fmt.Errorf("delete task %s: %v", id, err)
+		}
+	}
+
+	return ctr.Delete(ctx, containerd.WithSnapshotCleanup)
+}
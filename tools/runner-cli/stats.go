@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ContainerStats is a point-in-time resource sample for a single container,
+// normalized across whichever runtime backend produced it.
+type ContainerStats struct {
+	ContainerID string
+	Name        string
+	CPUPercent  float64
+	MemUsage    int64
+	MemLimit    int64
+	NetRxBytes  int64
+	NetTxBytes  int64
+}
+
+// dockerStatsFrame is one JSON object from the Docker/Podman-compat
+// `GET /containers/{id}/stats?stream=true` stream.
+type dockerStatsFrame struct {
+	CPUStats struct {
+		CPUUsage struct {
+			TotalUsage uint64 `json:"total_usage"`
+		} `json:"cpu_usage"`
+		SystemCPUUsage uint64 `json:"system_cpu_usage"`
+		OnlineCPUs     uint32 `json:"online_cpus"`
+	} `json:"cpu_stats"`
+	PreCPUStats struct {
+		CPUUsage struct {
+			TotalUsage uint64 `json:"total_usage"`
+		} `json:"cpu_usage"`
+		SystemCPUUsage uint64 `json:"system_cpu_usage"`
+	} `json:"precpu_stats"`
+	MemoryStats struct {
+		Usage int64 `json:"usage"`
+		Limit int64 `json:"limit"`
+	} `json:"memory_stats"`
+	Networks map[string]struct {
+		RxBytes int64 `json:"rx_bytes"`
+		TxBytes int64 `json:"tx_bytes"`
+	} `json:"networks"`
+}
+
+// dockerCPUPercent implements the standard `docker stats` CPU% formula:
+// (cpu_delta / system_delta) * online_cpus * 100.
+func dockerCPUPercent(f dockerStatsFrame) float64 {
+	cpuDelta := float64(f.CPUStats.CPUUsage.TotalUsage) - float64(f.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(f.CPUStats.SystemCPUUsage) - float64(f.PreCPUStats.SystemCPUUsage)
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+	onlineCPUs := float64(f.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = 1
+	}
+	return (cpuDelta / systemDelta) * onlineCPUs * 100
+}
+
+func sumNetworkIO(f dockerStatsFrame) (rx, tx int64) {
+	for _, n := range f.Networks {
+		rx += n.RxBytes
+		tx += n.TxBytes
+	}
+	return rx, tx
+}
+
+// streamContainerStats decodes a Docker/Podman-compat stats stream and
+// emits one ContainerStats per frame until the stream ends or ctx is
+// cancelled. Shared by dockerRuntime and podmanRuntime, which expose the
+// same compat API shape.
+func streamContainerStats(ctx context.Context, client *http.Client, apiBase, id string) (<-chan ContainerStats, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/containers/%s/stats?stream=true", apiBase, id), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("stats request failed: %v", err)
+	}
+
+	ch := make(chan ContainerStats)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+
+		dec := json.NewDecoder(resp.Body)
+		for {
+			var frame dockerStatsFrame
+			if err := dec.Decode(&frame); err != nil {
+				return
+			}
+			rx, tx := sumNetworkIO(frame)
+			sample := ContainerStats{
+				ContainerID: id,
+				CPUPercent:  dockerCPUPercent(frame),
+				MemUsage:    frame.MemoryStats.Usage,
+				MemLimit:    frame.MemoryStats.Limit,
+				NetRxBytes:  rx,
+				NetTxBytes:  tx,
+			}
+			select {
+			case ch <- sample:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// runStats streams live stats for every container matching prefix and
+// prints a rolling aggregate summary each interval, with a per-container
+// breakdown under verbose.
+func runStats(ctx context.Context, runtime ContainerRuntime, prefix string, interval time.Duration, verbose bool) error {
+	containers, err := runtime.List(prefix)
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %v", err)
+	}
+	if len(containers) == 0 {
+		fmt.Printf("No containers found with prefix '%s'\n", prefix)
+		return nil
+	}
+
+	fmt.Printf("Streaming stats for %d container(s) matching '%s' (runtime: %s)\n", len(containers), prefix, runtime.Name())
+
+	var (
+		mu     sync.Mutex
+		latest = make(map[string]ContainerStats, len(containers))
+		wg     sync.WaitGroup
+	)
+
+	for _, c := range containers {
+		c := c
+		ch, err := runtime.Stats(ctx, c.ID)
+		if err != nil {
+			fmt.Printf("⚠️  failed to stream stats for %s: %v\n", c.Name, err)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for sample := range ch {
+				sample.Name = c.Name
+				mu.Lock()
+				latest[c.ID] = sample
+				mu.Unlock()
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return nil
+		case <-ticker.C:
+			mu.Lock()
+			printStatsSummary(latest, verbose)
+			mu.Unlock()
+		}
+	}
+}
+
+func printStatsSummary(latest map[string]ContainerStats, verbose bool) {
+	var totalCPU float64
+	var totalMem, totalLimit, totalRx, totalTx int64
+	for _, s := range latest {
+		totalCPU += s.CPUPercent
+		totalMem += s.MemUsage
+		totalLimit += s.MemLimit
+		totalRx += s.NetRxBytes
+		totalTx += s.NetTxBytes
+	}
+
+	fmt.Printf("[%s] %d container(s)  CPU: %.1f%%  Mem: %.1f/%.1f MB  Net: rx=%.1fMB tx=%.1fMB\n",
+		time.Now().Format("15:04:05"), len(latest), totalCPU,
+		float64(totalMem)/1024/1024, float64(totalLimit)/1024/1024,
+		float64(totalRx)/1024/1024, float64(totalTx)/1024/1024)
+
+	if !verbose {
+		return
+	}
+	for _, s := range latest {
+		fmt.Printf("  %-30s cpu=%.1f%%  mem=%.1f/%.1fMB  rx=%.1fMB  tx=%.1fMB\n",
+			s.Name, s.CPUPercent, float64(s.MemUsage)/1024/1024, float64(s.MemLimit)/1024/1024,
+			float64(s.NetRxBytes)/1024/1024, float64(s.NetTxBytes)/1024/1024)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import "testing"
+
+func TestDockerCPUPercent(t *testing.T) {
+	frame := func(totalUsage, preTotalUsage, systemUsage, preSystemUsage uint64, onlineCPUs uint32) dockerStatsFrame {
+		var f dockerStatsFrame
+		f.CPUStats.CPUUsage.TotalUsage = totalUsage
+		f.CPUStats.SystemCPUUsage = systemUsage
+		f.CPUStats.OnlineCPUs = onlineCPUs
+		f.PreCPUStats.CPUUsage.TotalUsage = preTotalUsage
+		f.PreCPUStats.SystemCPUUsage = preSystemUsage
+		return f
+	}
+
+	tests := []struct {
+		name  string
+		frame dockerStatsFrame
+		want  float64
+	}{
+		{
+			name:  "half a cpu of a single-cpu container",
+			frame: frame(1500, 1000, 2000, 1000, 1),
+			want:  50,
+		},
+		{
+			name:  "scales with online cpu count",
+			frame: frame(3000, 1000, 2000, 1000, 4),
+			want:  800,
+		},
+		{
+			name:  "zero online cpus defaults to one",
+			frame: frame(1500, 1000, 2000, 1000, 0),
+			want:  50,
+		},
+		{
+			name:  "zero cpu delta guard",
+			frame: frame(1000, 1000, 2000, 1000, 1),
+			want:  0,
+		},
+		{
+			name:  "negative cpu delta guard",
+			frame: frame(900, 1000, 2000, 1000, 1),
+			want:  0,
+		},
+		{
+			name:  "zero system delta guard",
+			frame: frame(1500, 1000, 2000, 2000, 1),
+			want:  0,
+		},
+		{
+			name:  "negative system delta guard",
+			frame: frame(1500, 1000, 1000, 2000, 1),
+			want:  0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := dockerCPUPercent(tc.frame); got != tc.want {
+				t.Errorf("dockerCPUPercent() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
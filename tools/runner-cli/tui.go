@@ -0,0 +1,285 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const tuiHistorySize = 60
+
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// tuiState holds everything the dashboard redraws from: rolling history for
+// the sparklines, the latest health snapshot, and the currently displayed
+// (and filterable) container list. It's written by the refresh loop and the
+// cleanup hotkey, and read by redraw — all through mu, since refreshes and
+// redraws run on different goroutines than the tview event loop.
+type tuiState struct {
+	mu           sync.Mutex
+	queueHistory []int
+	runMsHistory []float64
+	lastHealth   healthResponse
+	containers   []Container
+	filter       string
+}
+
+func (s *tuiState) setFilter(filter string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.filter = filter
+}
+
+func (s *tuiState) getFilter() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.filter
+}
+
+func (s *tuiState) snapshot() (queueHistory []int, runMsHistory []float64, lastHealth healthResponse, containers []Container) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queueHistory, s.runMsHistory, s.lastHealth, s.containers
+}
+
+func (s *tuiState) recordHealth(health healthResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastHealth = health
+	s.queueHistory = appendCappedInt(s.queueHistory, health.QueueLength, tuiHistorySize)
+	s.runMsHistory = appendCappedFloat(s.runMsHistory, health.AvgRunMs, tuiHistorySize)
+}
+
+func (s *tuiState) recordContainers(containers []Container) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.containers = containers
+}
+
+// runTUI drives a full-screen, k9s-style dashboard for the runner service:
+// queue/latency sparklines, a pool utilization gauge, and a live container
+// table, with c/r/q// hotkeys for cleanup, refresh, quit, and filtering.
+func runTUI(serviceURL string, runtime ContainerRuntime, prefix string, interval time.Duration) error {
+	app := tview.NewApplication()
+	state := &tuiState{filter: prefix}
+
+	sparklineView := tview.NewTextView().SetDynamicColors(true)
+	sparklineView.SetBorder(true).SetTitle(" Queue Length / Avg Run Time (ms) ")
+
+	gauge := tview.NewTextView().SetDynamicColors(true)
+	gauge.SetBorder(true).SetTitle(" Pool Utilization ")
+
+	table := tview.NewTable().SetFixed(1, 0)
+	table.SetBorder(true).SetTitle(" Containers ")
+
+	help := tview.NewTextView().SetDynamicColors(true).
+		SetText("[yellow]c[white] cleanup   [yellow]r[white] refresh   [yellow]/[white] filter   [yellow]q[white] quit")
+
+	top := tview.NewFlex().
+		AddItem(sparklineView, 0, 2, false).
+		AddItem(gauge, 0, 1, false)
+
+	root := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(top, 8, 0, false).
+		AddItem(table, 0, 1, true).
+		AddItem(help, 1, 0, false)
+
+	filterField := tview.NewInputField().SetLabel("Filter prefix: ").SetText(state.getFilter())
+
+	pages := tview.NewPages().
+		AddPage("dashboard", root, true, true)
+
+	redraw := func() {
+		queueHistory, runMsHistory, lastHealth, containers := state.snapshot()
+		sparklineView.SetText(renderSparklines(queueHistory, runMsHistory))
+		gauge.SetText(renderGauge(lastHealth))
+		renderContainerTable(table, containers)
+	}
+
+	// refresh hits the network, so only one must run at a time; hotkeys and
+	// the ticker both funnel through requestRefresh into a single worker
+	// goroutine instead of spawning a fresh goroutine per keypress.
+	refresh := func() {
+		if health, err := fetchHealth(serviceURL); err == nil {
+			state.recordHealth(health)
+		}
+		if containers, err := runtime.List(state.getFilter()); err == nil {
+			state.recordContainers(containers)
+		}
+		app.QueueUpdateDraw(redraw)
+	}
+
+	refreshRequests := make(chan struct{}, 1)
+	requestRefresh := func() {
+		select {
+		case refreshRequests <- struct{}{}:
+		default:
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	stop := make(chan struct{})
+	go func() {
+		requestRefresh()
+		for {
+			select {
+			case <-ticker.C:
+				requestRefresh()
+			case <-refreshRequests:
+				refresh()
+			case <-stop:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	filterField.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			state.setFilter(filterField.GetText())
+			requestRefresh()
+		}
+		pages.SwitchToPage("dashboard")
+		app.SetFocus(table)
+	})
+
+	confirmCleanup := func() {
+		_, _, _, containers := state.snapshot()
+		if len(containers) == 0 {
+			return
+		}
+		modalText := fmt.Sprintf("Remove %d container(s) matching %q?", len(containers), state.getFilter())
+		confirm := tview.NewModal().
+			SetText(modalText).
+			AddButtons([]string{"Remove", "Cancel"}).
+			SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+				pages.RemovePage("confirm")
+				app.SetFocus(table)
+				if buttonLabel != "Remove" {
+					return
+				}
+				go func() {
+					for _, c := range containers {
+						_ = runtime.Remove(c.ID, true)
+					}
+					requestRefresh()
+				}()
+			})
+		pages.AddPage("confirm", confirm, true, true)
+		app.SetFocus(confirm)
+	}
+
+	root.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'q':
+			close(stop)
+			app.Stop()
+			return nil
+		case 'r':
+			requestRefresh()
+			return nil
+		case 'c':
+			confirmCleanup()
+			return nil
+		case '/':
+			pages.AddPage("filter", modal(filterField, 60, 3), true, true)
+			app.SetFocus(filterField)
+			return nil
+		}
+		return event
+	})
+
+	return app.SetRoot(pages, true).SetFocus(table).Run()
+}
+
+// modal centers a fixed-size primitive over the current page, used for the
+// filter input overlay.
+func modal(p tview.Primitive, width, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 0, true).
+			AddItem(nil, 0, 1, false), width, 0, true).
+		AddItem(nil, 0, 1, false)
+}
+
+func appendCappedInt(history []int, v, limit int) []int {
+	history = append(history, v)
+	if len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	return history
+}
+
+func appendCappedFloat(history []float64, v float64, limit int) []float64 {
+	history = append(history, v)
+	if len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	return history
+}
+
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		if max == min {
+			b.WriteRune(sparkTicks[0])
+			continue
+		}
+		idx := int((v - min) / (max - min) * float64(len(sparkTicks)-1))
+		b.WriteRune(sparkTicks[idx])
+	}
+	return b.String()
+}
+
+func renderSparklines(queueHistory []int, runMsHistory []float64) string {
+	queueValues := make([]float64, len(queueHistory))
+	for i, v := range queueHistory {
+		queueValues[i] = float64(v)
+	}
+	return fmt.Sprintf("Queue: %s\n\nAvg run: %s", sparkline(queueValues), sparkline(runMsHistory))
+}
+
+func renderGauge(health healthResponse) string {
+	if health.PoolSize == 0 {
+		return "no data yet"
+	}
+	util := float64(health.ActiveWorkers) / float64(health.PoolSize)
+	if util > 1 {
+		util = 1
+	}
+	const width = 24
+	filled := int(util * width)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+	return fmt.Sprintf("%s\n%d/%d workers (%.0f%%)", bar, health.ActiveWorkers, health.PoolSize, util*100)
+}
+
+func renderContainerTable(table *tview.Table, containers []Container) {
+	table.Clear()
+	headers := []string{"NAME", "IMAGE", "STATUS"}
+	for col, h := range headers {
+		table.SetCell(0, col, tview.NewTableCell(h).SetSelectable(false).SetTextColor(tcell.ColorYellow))
+	}
+	for row, c := range containers {
+		table.SetCell(row+1, 0, tview.NewTableCell(c.Name))
+		table.SetCell(row+1, 1, tview.NewTableCell(c.Image))
+		table.SetCell(row+1, 2, tview.NewTableCell(c.Status))
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+type watchFormat string
+
+const (
+	formatPretty watchFormat = "pretty"
+	formatJSON   watchFormat = "json"
+	formatNDJSON watchFormat = "ndjson"
+	formatCSV    watchFormat = "csv"
+)
+
+// watchOptions configures a single watchHealth run: how to render each
+// tick, where to send it, and how to smooth the running averages.
+type watchOptions struct {
+	format     watchFormat
+	out        io.Writer
+	alpha      float64
+	thresholds thresholds
+	alerter    *alerter
+}
+
+// watchRecord is one tick of `-watch` output: the raw health snapshot plus
+// deltas computed against the previous tick.
+type watchRecord struct {
+	Timestamp     time.Time      `json:"timestamp"`
+	Health        healthResponse `json:"health"`
+	RunsDelta     int            `json:"runsDelta"`
+	RunsPerSecond float64        `json:"runsPerSecond"`
+	QueueGrowth   int            `json:"queueGrowth"`
+	AvgRunMsEWMA  float64        `json:"avgRunMsEWMA"`
+}
+
+// watchState tracks what's needed to turn consecutive health snapshots into
+// deltas: the previous sample, and an EWMA of AvgRunMs seeded on first use.
+type watchState struct {
+	alpha     float64
+	seeded    bool
+	prevRuns  int
+	prevQueue int
+	prevTime  time.Time
+	ewma      float64
+}
+
+func newWatchState(alpha float64) *watchState {
+	return &watchState{alpha: alpha}
+}
+
+// observe folds a new health sample into the watcher's state and returns
+// the resulting record. A drop in TotalRuns means the runner service
+// restarted, so the counters are re-seeded rather than reported as a
+// negative delta.
+func (s *watchState) observe(health healthResponse, now time.Time) watchRecord {
+	rec := watchRecord{Timestamp: now, Health: health}
+
+	if !s.seeded || health.TotalRuns < s.prevRuns {
+		s.ewma = health.AvgRunMs
+		s.prevRuns = health.TotalRuns
+		s.prevQueue = health.QueueLength
+		s.prevTime = now
+		s.seeded = true
+		rec.AvgRunMsEWMA = s.ewma
+		return rec
+	}
+
+	elapsed := now.Sub(s.prevTime).Seconds()
+	runsDelta := health.TotalRuns - s.prevRuns
+	var runsPerSecond float64
+	if elapsed > 0 {
+		runsPerSecond = float64(runsDelta) / elapsed
+	}
+
+	s.ewma = s.alpha*health.AvgRunMs + (1-s.alpha)*s.ewma
+
+	rec.RunsDelta = runsDelta
+	rec.RunsPerSecond = runsPerSecond
+	rec.QueueGrowth = health.QueueLength - s.prevQueue
+	rec.AvgRunMsEWMA = s.ewma
+
+	s.prevRuns = health.TotalRuns
+	s.prevQueue = health.QueueLength
+	s.prevTime = now
+	return rec
+}
+
+func watchHealth(serviceURL string, interval time.Duration, opts watchOptions) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	state := newWatchState(opts.alpha)
+	csvHeaderWritten := false
+
+	if opts.format == formatPretty {
+		fmt.Printf("Watching runner service health (every %v)...\n", interval)
+		fmt.Println("Press Ctrl+C to stop")
+	}
+
+	for range ticker.C {
+		now := time.Now()
+		health, err := fetchHealth(serviceURL)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[%s] ❌ Error: %v\n", now.Format("15:04:05"), err)
+			continue
+		}
+
+		rec := state.observe(health, now)
+
+		for _, b := range opts.thresholds.evaluate(health, serviceURL, now) {
+			if opts.format == formatPretty {
+				fmt.Printf("🚨 THRESHOLD BREACHED: %s=%.2f (limit %.2f)\n", b.Metric, b.Value, b.Threshold)
+			}
+			opts.alerter.fire(b)
+		}
+
+		switch opts.format {
+		case formatJSON:
+			data, _ := json.MarshalIndent(rec, "", "  ")
+			fmt.Fprintln(opts.out, string(data))
+		case formatNDJSON:
+			data, _ := json.Marshal(rec)
+			fmt.Fprintln(opts.out, string(data))
+		case formatCSV:
+			if !csvHeaderWritten {
+				fmt.Fprintln(opts.out, "timestamp,status,poolSize,activeWorkers,queueLength,totalRuns,avgRunMs,avgQueueWaitMs,runsDelta,runsPerSecond,queueGrowth,avgRunMsEWMA")
+				csvHeaderWritten = true
+			}
+			fmt.Fprintf(opts.out, "%s,%s,%d,%d,%d,%d,%.2f,%.2f,%d,%.4f,%d,%.2f\n",
+				rec.Timestamp.Format(time.RFC3339), rec.Health.Status, rec.Health.PoolSize, rec.Health.ActiveWorkers,
+				rec.Health.QueueLength, rec.Health.TotalRuns, rec.Health.AvgRunMs, rec.Health.AvgQueueWaitMs,
+				rec.RunsDelta, rec.RunsPerSecond, rec.QueueGrowth, rec.AvgRunMsEWMA)
+		default:
+			fmt.Printf("[%s] ", now.Format("15:04:05"))
+			printHealth(health)
+			fmt.Printf("  Δruns=%d  runs/s=%.2f  Δqueue=%d  avgRunMs(ewma)=%.2f\n", rec.RunsDelta, rec.RunsPerSecond, rec.QueueGrowth, rec.AvgRunMsEWMA)
+			fmt.Println()
+		}
+	}
+}
+
+// rotatingWriter is an io.WriteCloser for -out that rotates to
+// <path>.1, <path>.2, ... once the file exceeds maxBytes, keeping at most
+// `keep` rotated files.
+type rotatingWriter struct {
+	path     string
+	maxBytes int64
+	keep     int
+	file     *os.File
+	size     int64
+}
+
+func newRotatingWriter(path string, maxBytes int64, keep int) (*rotatingWriter, error) {
+	w := &rotatingWriter{path: path, maxBytes: maxBytes, keep: keep}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *rotatingWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", w.path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.file = f
+	w.size = info.Size()
+	return nil
+}
+
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	if w.maxBytes > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxBytes {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *rotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	if w.keep <= 0 {
+		// Nothing to shift files into, so rotation just means "start this
+		// file over" rather than letting it grow unbounded.
+		if err := os.Truncate(w.path, 0); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return w.open()
+	}
+	for i := w.keep - 1; i >= 1; i-- {
+		older := fmt.Sprintf("%s.%d", w.path, i+1)
+		newer := fmt.Sprintf("%s.%d", w.path, i)
+		if _, err := os.Stat(newer); err == nil {
+			os.Rename(newer, older)
+		}
+	}
+	if _, err := os.Stat(w.path); err == nil {
+		os.Rename(w.path, fmt.Sprintf("%s.1", w.path))
+	}
+	return w.open()
+}
+
+func (w *rotatingWriter) Close() error {
+	return w.file.Close()
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWatchStateObserveSeedsOnFirstSample(t *testing.T) {
+	s := newWatchState(0.5)
+	now := time.Now()
+
+	rec := s.observe(healthResponse{TotalRuns: 10, QueueLength: 3, AvgRunMs: 42}, now)
+
+	if rec.AvgRunMsEWMA != 42 {
+		t.Errorf("first sample AvgRunMsEWMA = %v, want 42 (seeded straight from AvgRunMs)", rec.AvgRunMsEWMA)
+	}
+	if rec.RunsDelta != 0 || rec.RunsPerSecond != 0 || rec.QueueGrowth != 0 {
+		t.Errorf("first sample should report no deltas, got %+v", rec)
+	}
+}
+
+func TestWatchStateObserveComputesDeltas(t *testing.T) {
+	s := newWatchState(0.5)
+	t0 := time.Now()
+	s.observe(healthResponse{TotalRuns: 10, QueueLength: 3, AvgRunMs: 100}, t0)
+
+	t1 := t0.Add(2 * time.Second)
+	rec := s.observe(healthResponse{TotalRuns: 16, QueueLength: 5, AvgRunMs: 200}, t1)
+
+	if rec.RunsDelta != 6 {
+		t.Errorf("RunsDelta = %d, want 6", rec.RunsDelta)
+	}
+	if rec.RunsPerSecond != 3 {
+		t.Errorf("RunsPerSecond = %v, want 3 (6 runs / 2s)", rec.RunsPerSecond)
+	}
+	if rec.QueueGrowth != 2 {
+		t.Errorf("QueueGrowth = %d, want 2", rec.QueueGrowth)
+	}
+	wantEWMA := 0.5*200 + 0.5*100
+	if rec.AvgRunMsEWMA != wantEWMA {
+		t.Errorf("AvgRunMsEWMA = %v, want %v", rec.AvgRunMsEWMA, wantEWMA)
+	}
+}
+
+func TestWatchStateObserveReseedsOnCounterReset(t *testing.T) {
+	s := newWatchState(0.5)
+	t0 := time.Now()
+	s.observe(healthResponse{TotalRuns: 100, QueueLength: 9, AvgRunMs: 500}, t0)
+
+	// Service restarted: TotalRuns dropped below the previous sample.
+	t1 := t0.Add(time.Second)
+	rec := s.observe(healthResponse{TotalRuns: 2, QueueLength: 1, AvgRunMs: 10}, t1)
+
+	if rec.RunsDelta != 0 || rec.RunsPerSecond != 0 || rec.QueueGrowth != 0 {
+		t.Errorf("reseed tick should report no deltas, got %+v", rec)
+	}
+	if rec.AvgRunMsEWMA != 10 {
+		t.Errorf("reseed tick AvgRunMsEWMA = %v, want 10 (re-seeded from AvgRunMs, not blended with stale EWMA)", rec.AvgRunMsEWMA)
+	}
+
+	// A normal tick after the reseed should resume blending from the new baseline.
+	t2 := t1.Add(time.Second)
+	rec2 := s.observe(healthResponse{TotalRuns: 4, QueueLength: 2, AvgRunMs: 20}, t2)
+	if rec2.RunsDelta != 2 {
+		t.Errorf("post-reseed RunsDelta = %d, want 2", rec2.RunsDelta)
+	}
+	wantEWMA := 0.5*20 + 0.5*10
+	if rec2.AvgRunMsEWMA != wantEWMA {
+		t.Errorf("post-reseed AvgRunMsEWMA = %v, want %v", rec2.AvgRunMsEWMA, wantEWMA)
+	}
+}